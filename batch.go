@@ -0,0 +1,181 @@
+package merkle
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// MaxBatchProofSiblings bounds the number of sibling hashes a BatchProof
+// may carry, analogous to Tendermint's MaxAunts, so a malicious proof
+// can't force unbounded verifier work.
+const MaxBatchProofSiblings = 256
+
+// BatchProof is a single combined membership proof for several leaves.
+// It omits any sibling hash that is itself derivable from another leaf
+// in the batch, so it costs far fewer than len(Indices)*log(Total) hashes.
+type BatchProof struct {
+	Indices []int
+	Hashes  [][]byte // leaf hashes, parallel to Indices
+	Total   int      // number of leaves in the tree the proof was made against
+	Sibs    [][]byte // proof stream consumed level-by-level; nil marks "no sibling"
+}
+
+func NewBatchProof(indices []int, hashes [][]byte, total int, sibs [][]byte) *BatchProof {
+	return &BatchProof{indices, hashes, total, sibs}
+}
+
+func (t *Tree) hashNode(parts ...[]byte) []byte {
+	h := t.HashMaker()
+	h.Reset()
+	if t.domainSep {
+		h.Write([]byte{nodePrefix})
+	}
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+func ceilHalf(n int) int {
+	return (n + n%2) / 2
+}
+
+func sortedIndices(marked map[int][]byte) []int {
+	indices := make([]int, 0, len(marked))
+	for i := range marked {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// ComputeProofBatch builds one combined proof that vals are all leaves of
+// the tree. At each level it includes a sibling hash only if that
+// sibling isn't itself one of the marked (proven) positions, since the
+// verifier can derive it from another leaf instead.
+func (t *Tree) ComputeProofBatch(vals [][]byte) (*BatchProof, error) {
+	if len(vals) == 0 {
+		return nil, errors.New("No vals")
+	}
+	height := t.Height()
+	leaves, err := t.level(height)
+	if err != nil {
+		return nil, err
+	}
+	marked := make(map[int][]byte, len(vals))
+	for _, val := range vals {
+		hh := t.HashMaker()
+		hh.Reset()
+		if t.domainSep {
+			hh.Write([]byte{leafPrefix})
+		}
+		hh.Write(val)
+		h := hh.Sum(nil)
+		idx := -1
+		for i := range leaves {
+			if bytes.Equal(leaves[i].h, h) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, errors.New("Val not found")
+		}
+		marked[idx] = h
+	}
+	indices := sortedIndices(marked)
+	hashes := make([][]byte, len(indices))
+	for i, idx := range indices {
+		hashes[i] = marked[idx]
+	}
+	var sibs [][]byte
+	level := leaves
+	for h := height; h > 1; h-- {
+		next := make(map[int][]byte, len(marked))
+		for _, i := range sortedIndices(marked) {
+			sibIdx := i ^ 1
+			if _, ok := marked[sibIdx]; !ok {
+				if sibIdx < len(level) {
+					sibs = append(sibs, level[sibIdx].h)
+				} else {
+					sibs = append(sibs, nil)
+				}
+				if len(sibs) > MaxBatchProofSiblings {
+					return nil, errors.New("Batch too large")
+				}
+			}
+			next[i/2] = nil
+		}
+		marked = next
+		lv, err := t.level(h - 1)
+		if err != nil {
+			return nil, err
+		}
+		level = lv
+	}
+	return NewBatchProof(indices, hashes, len(leaves), sibs), nil
+}
+
+// VerifyProofBatch reports whether bp proves its leaves are all members
+// of t. It mirrors ComputeProofBatch's walk: at each level it pairs two
+// already-known hashes when both siblings were proven, or a known hash
+// with the next hash off the proof stream otherwise.
+func (t *Tree) VerifyProofBatch(bp *BatchProof) bool {
+	if len(bp.Indices) == 0 || len(bp.Indices) != len(bp.Hashes) {
+		return false
+	}
+	if len(bp.Sibs) > MaxBatchProofSiblings {
+		return false
+	}
+	height := t.Height()
+	leaves, err := t.level(height)
+	if err != nil || len(leaves) != bp.Total {
+		return false
+	}
+	known := make(map[int][]byte, len(bp.Indices))
+	for i, idx := range bp.Indices {
+		if idx < 0 || idx >= bp.Total {
+			return false
+		}
+		known[idx] = bp.Hashes[i]
+	}
+	levelLen := len(leaves)
+	pos := 0
+	for h := height; h > 1; h-- {
+		next := make(map[int][]byte, len(known))
+		for _, i := range sortedIndices(known) {
+			parent := i / 2
+			if _, done := next[parent]; done {
+				continue
+			}
+			sibIdx := i ^ 1
+			sibHash, ok := known[sibIdx]
+			if !ok {
+				if pos >= len(bp.Sibs) {
+					return false
+				}
+				sibHash = bp.Sibs[pos]
+				pos++
+			}
+			var ph []byte
+			switch {
+			case sibIdx >= levelLen:
+				ph = t.hashNode(known[i])
+			case i&1 == 0:
+				ph = t.hashNode(known[i], sibHash)
+			default:
+				ph = t.hashNode(sibHash, known[i])
+			}
+			next[parent] = ph
+		}
+		known = next
+		levelLen = ceilHalf(levelLen)
+	}
+	if pos != len(bp.Sibs) {
+		return false
+	}
+	root, ok := known[0]
+	return ok && bytes.Equal(root, t.root().h)
+}
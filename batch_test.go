@@ -0,0 +1,92 @@
+package merkle
+
+import (
+	"testing"
+)
+
+func TestComputeProofBatchVerifyProofBatchRoundTrip(t *testing.T) {
+	for _, n := range testLeafCounts {
+		for _, domainSep := range []bool{false, true} {
+			vals := leafVals(n)
+			tree := newTestTree(domainSep)
+			if _, err := tree.Construct(vals); err != nil {
+				t.Fatalf("n=%d domainSep=%v: Construct: %v", n, domainSep, err)
+			}
+			// Batch of every other leaf, plus the last one, so both
+			// contiguous and scattered indices are exercised.
+			var batch [][]byte
+			for i := 0; i < n; i += 2 {
+				batch = append(batch, vals[i])
+			}
+			if (n-1)%2 != 0 {
+				batch = append(batch, vals[n-1])
+			}
+			bp, err := tree.ComputeProofBatch(batch)
+			if err != nil {
+				t.Fatalf("n=%d domainSep=%v: ComputeProofBatch: %v", n, domainSep, err)
+			}
+			if !tree.VerifyProofBatch(bp) {
+				t.Fatalf("n=%d domainSep=%v: VerifyProofBatch returned false", n, domainSep)
+			}
+		}
+	}
+}
+
+func TestComputeProofBatchAllLeaves(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 9, 16} {
+		vals := leafVals(n)
+		tree := newTestTree(false)
+		if _, err := tree.Construct(vals); err != nil {
+			t.Fatalf("n=%d: Construct: %v", n, err)
+		}
+		bp, err := tree.ComputeProofBatch(vals)
+		if err != nil {
+			t.Fatalf("n=%d: ComputeProofBatch: %v", n, err)
+		}
+		if !tree.VerifyProofBatch(bp) {
+			t.Fatalf("n=%d: VerifyProofBatch returned false for a full batch", n)
+		}
+	}
+}
+
+func TestComputeProofBatchValNotFound(t *testing.T) {
+	tree := newTestTree(false)
+	if _, err := tree.Construct(leafVals(5)); err != nil {
+		t.Fatalf("Construct: %v", err)
+	}
+	if _, err := tree.ComputeProofBatch([][]byte{[]byte("not-a-leaf")}); err == nil {
+		t.Fatal("expected error for a val that isn't a leaf")
+	}
+}
+
+func TestVerifyProofBatchRejectsTamperedHash(t *testing.T) {
+	tree := newTestTree(false)
+	vals := leafVals(9)
+	if _, err := tree.Construct(vals); err != nil {
+		t.Fatalf("Construct: %v", err)
+	}
+	bp, err := tree.ComputeProofBatch(vals[:3])
+	if err != nil {
+		t.Fatalf("ComputeProofBatch: %v", err)
+	}
+	bp.Hashes[0][0] ^= 0xFF
+	if tree.VerifyProofBatch(bp) {
+		t.Fatal("VerifyProofBatch accepted a tampered leaf hash")
+	}
+}
+
+func TestVerifyProofBatchRejectsWrongTotal(t *testing.T) {
+	tree := newTestTree(false)
+	vals := leafVals(9)
+	if _, err := tree.Construct(vals); err != nil {
+		t.Fatalf("Construct: %v", err)
+	}
+	bp, err := tree.ComputeProofBatch(vals[:3])
+	if err != nil {
+		t.Fatalf("ComputeProofBatch: %v", err)
+	}
+	bp.Total++
+	if tree.VerifyProofBatch(bp) {
+		t.Fatal("VerifyProofBatch accepted a proof with a mismatched total")
+	}
+}
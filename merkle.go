@@ -32,43 +32,49 @@ func (l Level) String() string {
 	return buf.String()
 }
 
-func hashing(nd *Node, hash hash.Hash) ([]byte, error) {
-	n := nd
-	var h []byte //specify hash size
-	for {
-		if n.h != nil {
-			if n == nd {
-				return n.h, nil
-			}
-			n = n.parent
-			continue
-		} else if n.IsLeaf() {
-			return nil, errors.New("Leaf node does not have value")
-		} else if n.left.h == nil {
-			n = n.left
-			continue
-		}
-		h = n.left.h
-		if n.right != nil {
-			if n.right.h == nil {
-				n = n.right
-				continue
-			}
-			h = append(h, n.right.h...)
-		}
-		hash.Reset()
-		hash.Write(h)
-		n.h = hash.Sum(nil)
-		if n == nd {
-			return n.h, nil
-		}
-		n = n.parent
-	}
-}
+// leafPrefix and nodePrefix domain-separate leaf and internal-node hashing
+// per RFC 6962, so an internal node's hash can never be replayed as a leaf.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
 
 type Tree struct {
-	hash   hash.Hash
-	levels []Level
+	// HashMaker returns the hash.Hash to hash with. Construct calls it
+	// once per worker goroutine so leaves and levels can be hashed in
+	// parallel without sharing a hash.Hash across goroutines; callers
+	// that want real parallelism must return a fresh instance each time,
+	// not a closure over a single shared one.
+	HashMaker func() hash.Hash
+	levels    []Level
+	domainSep bool
+
+	// sharedHasher is true when HashMaker is known to return the same
+	// hash.Hash instance on every call (NewTree's and NewTreeRFC6962's
+	// thin wrapper), so the parallel paths in parallel.go know to fall
+	// back to a single worker instead of handing that instance to
+	// multiple goroutines at once.
+	sharedHasher bool
+
+	// simpleVals and simpleRoot hold the state for the ConstructSimple
+	// family, which builds a deterministic unbalanced tree independent
+	// of the Node/Level structure above.
+	simpleVals [][]byte
+	simpleRoot []byte
+
+	// pushStack, pushCount and the track* fields hold the state for the
+	// Push/Root incremental-construction family, which is independent of
+	// both the Node/Level structure and ConstructSimple's state above.
+	pushStack   []subtree
+	pushCount   int
+	trackIndex  *int
+	trackLeaf   []byte
+	trackBranch Branch
+
+	// streamBuilt is true once ConstructFromReader has built a root. It
+	// guards re-entry the same way Construct's t.Empty() check does, since
+	// streamRoot never populates levels for t.Empty() to catch on its own.
+	streamBuilt bool
 }
 
 type Branch [][]byte
@@ -82,8 +88,28 @@ func (b Branch) String() string {
 	return buf.String()
 }
 
-func NewTree(hash hash.Hash) *Tree {
-	return &Tree{hash: hash}
+// NewTree returns a Tree that hashes everything with h. This is a thin
+// wrapper around HashMaker that always returns the same instance, so it's
+// only safe for sequential use; pass a HashMaker that makes a fresh
+// hash.Hash per call (see NewTreeHashMaker) to use Construct's parallel path.
+func NewTree(h hash.Hash) *Tree {
+	return &Tree{HashMaker: func() hash.Hash { return h }, sharedHasher: true}
+}
+
+// NewTreeHashMaker returns a Tree that calls hashMaker to obtain a fresh
+// hash.Hash whenever one is needed, enabling Construct to hash leaves and
+// levels concurrently across goroutines, each with its own instance.
+func NewTreeHashMaker(hashMaker func() hash.Hash) *Tree {
+	return &Tree{HashMaker: hashMaker}
+}
+
+// NewTreeRFC6962 returns a Tree that hashes leaves and internal nodes with
+// the domain-separated scheme from RFC 6962 (Certificate Transparency):
+// leaves are hashed as H(0x00 || val) and internal nodes as
+// H(0x01 || left || right), so an internal node's hash can never be
+// reinterpreted as a leaf's.
+func NewTreeRFC6962(h hash.Hash) *Tree {
+	return &Tree{HashMaker: func() hash.Hash { return h }, domainSep: true, sharedHasher: true}
 }
 
 func (t *Tree) String() string {
@@ -128,25 +154,34 @@ func (p *Proof) String() string {
 }
 
 func (t *Tree) ComputeProof(val []byte) (*Proof, error) {
-	t.hash.Reset()
-	t.hash.Write(val)
-	h := t.hash.Sum(nil)
+	hh := t.HashMaker()
+	hh.Reset()
+	if t.domainSep {
+		hh.Write([]byte{leafPrefix})
+	}
+	hh.Write(val)
+	h := hh.Sum(nil)
 	height := t.Height()
 	leaves, err := t.level(height)
 	if err != nil {
 		return nil, err
 	}
-	var i int
-	for i, _ = range leaves {
-		if bytes.Equal(leaves[i].h, h) {
+	i := -1
+	for j := range leaves {
+		if bytes.Equal(leaves[j].h, h) {
+			i = j
 			break
 		}
 	}
-	if i == len(leaves) {
+	if i == -1 {
 		return nil, errors.New("Val not found")
 	}
 	var br Branch
-	if (i^1)&1 == 0 {
+	if i^1 >= len(leaves) {
+		// Odd node out at this level: it has no sibling, so VerifyProof
+		// just rehashes it alone (matching Construct/hashLevelParallel).
+		br = append(br, nil)
+	} else if (i^1)&1 == 0 {
 		br = append(br, append([]byte{0}, leaves[i^1].h...))
 	} else {
 		br = append(br, append([]byte{1}, leaves[i^1].h...))
@@ -162,7 +197,9 @@ func (t *Tree) ComputeProof(val []byte) (*Proof, error) {
 			// We hit root... break
 			break
 		}
-		if (i^1)&1 == 0 {
+		if i^1 >= len(level) {
+			br = append(br, nil)
+		} else if (i^1)&1 == 0 {
 			br = append(br, append([]byte{0}, level[i^1].h...))
 		} else {
 			br = append(br, append([]byte{1}, level[i^1].h...))
@@ -172,15 +209,20 @@ func (t *Tree) ComputeProof(val []byte) (*Proof, error) {
 	return proof, nil
 }
 
+// VerifyProof walks the branch in a local copy rather than mutating p.h,
+// and takes one hash.Hash from HashMaker for the whole walk. Concurrent
+// verifications against the same Tree are race-free only when HashMaker
+// returns a fresh instance per call; NewTree's and NewTreeRFC6962's
+// wrapper returns the same shared instance every time.
 func (t *Tree) VerifyProof(p *Proof) bool {
+	hh := t.HashMaker()
+	ph := p.h
 	for _, h := range p.br {
 		if h != nil {
 			if h[0] == 0 {
-				h = h[1:]
-				p.h = append(h, p.h...)
+				ph = append(h[1:], ph...)
 			} else if h[0] == 1 {
-				h = h[1:]
-				p.h = append(p.h, h...)
+				ph = append(ph, h[1:]...)
 			} else {
 				// shouldn't get here
 			}
@@ -188,13 +230,15 @@ func (t *Tree) VerifyProof(p *Proof) bool {
 			// just hash the previous hash
 			// should we ever get here?
 		}
-		t.hash.Reset()
-		t.hash.Write(p.h)
-		p.h = t.hash.Sum(nil)
+		hh.Reset()
+		if t.domainSep {
+			hh.Write([]byte{nodePrefix})
+		}
+		hh.Write(ph)
+		ph = hh.Sum(nil)
 	}
 	root := t.root()
-	match := bytes.Equal(root.h, p.h)
-	return match
+	return bytes.Equal(root.h, ph)
 }
 
 // Calculates height of tree and creates that many levels
@@ -213,32 +257,15 @@ func (t *Tree) Construct(vals [][]byte) ([]byte, error) {
 	count := len(vals)
 	height := calcTreeHeight(count)
 	t.levels = make([]Level, height)
-	height--
-	t.levels[height] = make(Level, count)
-	for i, val := range vals {
-		// For leaf nodes, we just hash the vals
-		t.hash.Reset()
-		t.hash.Write(val)
-		h := t.hash.Sum(nil)
-		t.levels[height][i] = &Node{h: h}
-	}
-	for height > 0 {
-		children := t.levels[height]
-		height--
-		t.levels[height] = constructLevel(children)
-	}
-	h, err := t.setHashes(t.hash)
-	if err != nil {
-		return nil, err
+	leafHeight := height - 1
+	t.levels[leafHeight] = make(Level, count)
+	t.hashLeavesParallel(vals, t.levels[leafHeight])
+	for h := leafHeight; h > 0; h-- {
+		children := t.levels[h]
+		t.levels[h-1] = constructLevel(children)
 	}
-	return h, nil
-}
-
-// Set hash of each non-leaf node
-// Hash the concatenation of children hashes
-func (t *Tree) setHashes(hash hash.Hash) ([]byte, error) {
-	root := t.root()
-	return hashing(root, hash)
+	t.hashLevelsParallel()
+	return t.root().h, nil
 }
 
 func constructLevel(children Level) Level {
@@ -0,0 +1,83 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func leafVals(n int) [][]byte {
+	vals := make([][]byte, n)
+	for i := range vals {
+		vals[i] = []byte(fmt.Sprintf("leaf-%d", i))
+	}
+	return vals
+}
+
+// testLeafCounts are the leaf counts exercised by round-trip tests across
+// the package: enough small balanced and unbalanced sizes, plus a few
+// counts either side of a power of two, to catch off-by-one errors in
+// odd-level handling.
+var testLeafCounts = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 15, 16, 17}
+
+// newTestTree returns a Tree hashing with SHA-256, either plain (NewTree)
+// or RFC 6962 domain-separated (NewTreeRFC6962), so round-trip tests can
+// exercise both hashing modes with the same loop body.
+func newTestTree(domainSep bool) *Tree {
+	if domainSep {
+		return NewTreeRFC6962(sha256.New())
+	}
+	return NewTree(sha256.New())
+}
+
+func TestComputeProofVerifyProofRoundTrip(t *testing.T) {
+	counts := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 15, 16, 17}
+	for _, n := range counts {
+		for _, domainSep := range []bool{false, true} {
+			vals := leafVals(n)
+			var tree *Tree
+			if domainSep {
+				tree = NewTreeRFC6962(sha256.New())
+			} else {
+				tree = NewTree(sha256.New())
+			}
+			if _, err := tree.Construct(vals); err != nil {
+				t.Fatalf("n=%d domainSep=%v: Construct: %v", n, domainSep, err)
+			}
+			for _, val := range vals {
+				p, err := tree.ComputeProof(val)
+				if err != nil {
+					t.Fatalf("n=%d domainSep=%v val=%s: ComputeProof: %v", n, domainSep, val, err)
+				}
+				if !tree.VerifyProof(p) {
+					t.Fatalf("n=%d domainSep=%v val=%s: VerifyProof returned false", n, domainSep, val)
+				}
+			}
+		}
+	}
+}
+
+func TestComputeProofValNotFound(t *testing.T) {
+	tree := NewTree(sha256.New())
+	if _, err := tree.Construct(leafVals(5)); err != nil {
+		t.Fatalf("Construct: %v", err)
+	}
+	if _, err := tree.ComputeProof([]byte("not-a-leaf")); err == nil {
+		t.Fatal("expected error for a val that isn't a leaf")
+	}
+}
+
+func TestVerifyProofRejectsTamperedHash(t *testing.T) {
+	tree := NewTree(sha256.New())
+	if _, err := tree.Construct(leafVals(7)); err != nil {
+		t.Fatalf("Construct: %v", err)
+	}
+	p, err := tree.ComputeProof([]byte("leaf-3"))
+	if err != nil {
+		t.Fatalf("ComputeProof: %v", err)
+	}
+	p.h[0] ^= 0xFF
+	if tree.VerifyProof(p) {
+		t.Fatal("VerifyProof accepted a tampered leaf hash")
+	}
+}
@@ -0,0 +1,97 @@
+package merkle
+
+import (
+	"runtime"
+	"sync"
+)
+
+// numWorkers bounds parallel work to the number of available CPUs, but
+// never spawns more workers than there is work to hand out. t.sharedHasher
+// marks a Tree whose HashMaker returns the same hash.Hash instance on
+// every call (NewTree's and NewTreeRFC6962's thin wrapper, kept for
+// backward compatibility with a single shared hasher); handing that
+// instance to multiple goroutines at once would corrupt it, so we fall
+// back to a single worker in that case. We can't detect this by calling
+// HashMaker and comparing the results, since a legal fresh-instance
+// HashMaker may return a hash.Hash that isn't comparable (e.g. one
+// holding a slice or map field) and comparing it would panic.
+func (t *Tree) numWorkers(n int) int {
+	w := runtime.NumCPU()
+	if w > n {
+		w = n
+	}
+	if w < 1 {
+		w = 1
+	}
+	if w > 1 && t.sharedHasher {
+		return 1
+	}
+	return w
+}
+
+// hashLeavesParallel hashes vals into leaves, sharding the work across
+// t.numWorkers(len(vals)) goroutines, each with its own hash.Hash from
+// HashMaker so none of them share hasher state.
+func (t *Tree) hashLeavesParallel(vals [][]byte, leaves Level) {
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := t.numWorkers(len(vals)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := t.HashMaker()
+			for i := range indices {
+				h.Reset()
+				if t.domainSep {
+					h.Write([]byte{leafPrefix})
+				}
+				h.Write(vals[i])
+				leaves[i] = &Node{h: h.Sum(nil)}
+			}
+		}()
+	}
+	for i := range vals {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
+
+// hashLevelsParallel sets the hash of every internal node, level by level
+// from just above the leaves up to the root. Each level only depends on
+// the one below it, which is already hashed by the time we reach it, so
+// all nodes within a level can be hashed concurrently.
+func (t *Tree) hashLevelsParallel() {
+	for height := len(t.levels) - 2; height >= 0; height-- {
+		t.hashLevelParallel(t.levels[height])
+	}
+}
+
+func (t *Tree) hashLevelParallel(level Level) {
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := t.numWorkers(len(level)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := t.HashMaker()
+			for i := range indices {
+				nd := level[i]
+				h.Reset()
+				if t.domainSep {
+					h.Write([]byte{nodePrefix})
+				}
+				h.Write(nd.left.h)
+				if nd.right != nil {
+					h.Write(nd.right.h)
+				}
+				nd.h = h.Sum(nil)
+			}
+		}()
+	}
+	for i := range level {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
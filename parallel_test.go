@@ -0,0 +1,85 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+// TestConstructParallelMatchesSequential checks that building a tree via
+// NewTreeHashMaker (which lets Construct hash leaves and levels across
+// multiple goroutines) yields the same root as NewTree's single-worker
+// path, for both balanced and unbalanced leaf counts. Run with -race to
+// catch any sharing of hasher state across goroutines.
+func TestConstructParallelMatchesSequential(t *testing.T) {
+	counts := []int{1, 2, 3, 7, 8, 9, 64, 65, 1000}
+	for _, n := range counts {
+		vals := leafVals(n)
+		seq := NewTree(sha256.New())
+		seqRoot, err := seq.Construct(vals)
+		if err != nil {
+			t.Fatalf("n=%d: sequential Construct: %v", n, err)
+		}
+		par := NewTreeHashMaker(sha256.New)
+		parRoot, err := par.Construct(vals)
+		if err != nil {
+			t.Fatalf("n=%d: parallel Construct: %v", n, err)
+		}
+		if string(seqRoot) != string(parRoot) {
+			t.Fatalf("n=%d: root mismatch: sequential %x, parallel %x", n, seqRoot, parRoot)
+		}
+	}
+}
+
+// benchVals builds n distinct leaf values once, outside the benchmark loop.
+func benchVals(n int) [][]byte {
+	vals := make([][]byte, n)
+	for i := range vals {
+		vals[i] = []byte(fmt.Sprintf("leaf-%d", i))
+	}
+	return vals
+}
+
+// BenchmarkConstructSequential64K measures Construct with a shared
+// hash.Hash (NewTree), which numWorkers forces onto a single worker, as
+// the baseline for BenchmarkConstructParallel64K.
+func BenchmarkConstructSequential64K(b *testing.B) {
+	vals := benchVals(64 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := NewTree(sha256.New())
+		if _, err := tree.Construct(vals); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkConstructParallel64K measures Construct with a fresh
+// hash.Hash per worker (NewTreeHashMaker), which lets hashLeavesParallel
+// and hashLevelParallel spread work across runtime.NumCPU() goroutines.
+// Run with -cpu 1,2,4,8 to see it approach near-linear speedup over
+// BenchmarkConstructSequential64K as cores increase.
+func BenchmarkConstructParallel64K(b *testing.B) {
+	vals := benchVals(64 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := NewTreeHashMaker(sha256.New)
+		if _, err := tree.Construct(vals); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkConstructParallel256K is a larger sibling of
+// BenchmarkConstructParallel64K for observing speedup at a size where
+// per-goroutine setup cost is even more thoroughly amortized.
+func BenchmarkConstructParallel256K(b *testing.B) {
+	vals := benchVals(256 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := NewTreeHashMaker(sha256.New)
+		if _, err := tree.Construct(vals); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
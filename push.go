@@ -0,0 +1,162 @@
+package merkle
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// subtree is a completed, already-hashed node somewhere in the tree,
+// identified by its height above the leaves. isTracked marks a subtree
+// that contains the leaf designated by TrackProof, so combine steps know
+// to record a sibling for it.
+type subtree struct {
+	h         []byte
+	height    int
+	isTracked bool
+}
+
+// combine hashes two child hashes into their parent, honoring domain
+// separation the same way Construct and hashNode do.
+func (t *Tree) combine(left, right []byte) []byte {
+	return t.hashNode(left, right)
+}
+
+// collapseStack repeatedly combines the top two stack entries while they
+// share the same height, leaving at most one entry per height. If one of
+// the combined entries is tracked, it records the other as a sibling in
+// trackBranch and marks the combined entry tracked in turn.
+func (t *Tree) collapseStack(stack []subtree) []subtree {
+	for len(stack) >= 2 {
+		top, next := stack[len(stack)-1], stack[len(stack)-2]
+		if top.height != next.height {
+			break
+		}
+		combined := subtree{h: t.combine(next.h, top.h), height: top.height + 1}
+		switch {
+		case next.isTracked:
+			t.trackBranch = append(t.trackBranch, append([]byte{1}, top.h...))
+			combined.isTracked = true
+		case top.isTracked:
+			t.trackBranch = append(t.trackBranch, append([]byte{0}, next.h...))
+			combined.isTracked = true
+		}
+		stack = append(stack[:len(stack)-2], combined)
+	}
+	return stack
+}
+
+// foldStack combines any remaining subtree roots right-to-left into a
+// single root hash, without requiring them to share a height. It extends
+// trackBranch the same way collapseStack does.
+func (t *Tree) foldStack(stack []subtree) []byte {
+	acc := stack[len(stack)-1]
+	for i := len(stack) - 2; i >= 0; i-- {
+		left := stack[i]
+		switch {
+		case acc.isTracked:
+			t.trackBranch = append(t.trackBranch, append([]byte{0}, left.h...))
+		case left.isTracked:
+			t.trackBranch = append(t.trackBranch, append([]byte{1}, acc.h...))
+		}
+		acc = subtree{
+			h:         t.combine(left.h, acc.h),
+			height:    left.height + 1,
+			isTracked: left.isTracked || acc.isTracked,
+		}
+	}
+	return acc.h
+}
+
+// Push adds a leaf to an incrementally-built tree with O(log N) amortized
+// work and O(log N) memory: it hashes val into a leaf and folds it onto a
+// stack of completed subtree roots, combining pairs at the same height as
+// soon as they appear. Call Root once all leaves have been pushed.
+func (t *Tree) Push(val []byte) {
+	h := t.HashMaker()
+	h.Reset()
+	if t.domainSep {
+		h.Write([]byte{leafPrefix})
+	}
+	h.Write(val)
+	s := subtree{h: h.Sum(nil)}
+	if t.trackIndex != nil && *t.trackIndex == t.pushCount {
+		s.isTracked = true
+		t.trackLeaf = s.h
+	}
+	t.pushCount++
+	t.pushOnto(s)
+}
+
+// PushSubtreeRoot grafts an already-hashed subtree of the given height
+// directly onto the stack without rehashing it, so a caller holding a
+// cached subtree root (e.g. a previously-hashed file segment) can resume
+// construction across process restarts. The subtree's 1<<height leaves are
+// counted in pushCount so later TrackProof indices still line up.
+func (t *Tree) PushSubtreeRoot(h []byte, height int) {
+	t.pushCount += 1 << uint(height)
+	t.pushOnto(subtree{h: h, height: height})
+}
+
+func (t *Tree) pushOnto(s subtree) {
+	t.pushStack = append(t.pushStack, s)
+	t.pushStack = t.collapseStack(t.pushStack)
+}
+
+// Root finalizes an incrementally-built tree by folding the remaining
+// subtree roots right-to-left and returns the result.
+func (t *Tree) Root() ([]byte, error) {
+	if len(t.pushStack) == 0 {
+		return nil, errors.New("No vals")
+	}
+	return t.foldStack(t.pushStack), nil
+}
+
+// TrackProof designates the index-th pushed leaf (0-based, over the
+// leaves pushed so far and still to come) as the subject of an
+// authentication path. Call it before that leaf is pushed; the path
+// accumulates in trackBranch as later Pushes and the final Root combine
+// it into larger subtrees. Call Proof afterward to retrieve it.
+func (t *Tree) TrackProof(index int) {
+	t.trackIndex = &index
+	t.trackBranch = nil
+	t.trackLeaf = nil
+}
+
+// Proof returns the authentication path accumulated for the leaf
+// designated by TrackProof, once Root has folded the final stack.
+func (t *Tree) Proof() (*Proof, error) {
+	if t.trackIndex == nil || t.trackLeaf == nil {
+		return nil, errors.New("No tracked leaf")
+	}
+	br := make(Branch, len(t.trackBranch))
+	copy(br, t.trackBranch)
+	return NewProof(br, t.trackLeaf), nil
+}
+
+// VerifyProofRoot walks p's branch the same way VerifyProof does, but
+// compares the result against an explicit root hash rather than reading
+// one off a Construct-built Node graph. Push/Root-based trees never
+// build that graph, so this is their verification path.
+func (t *Tree) VerifyProofRoot(p *Proof, root []byte) bool {
+	hh := t.HashMaker()
+	ph := p.h
+	for _, h := range p.br {
+		if len(h) > 0 {
+			if h[0] == 0 {
+				ph = append(h[1:], ph...)
+			} else {
+				ph = append(ph, h[1:]...)
+			}
+		} else {
+			// just hash the previous hash
+		}
+		hh.Reset()
+		if t.domainSep {
+			hh.Write([]byte{nodePrefix})
+		}
+		hh.Write(ph)
+		ph = hh.Sum(nil)
+	}
+	return bytes.Equal(ph, root)
+}
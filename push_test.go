@@ -0,0 +1,146 @@
+package merkle
+
+import (
+	"testing"
+)
+
+// TestPushRootDeterministic checks that pushing the same vals in the same
+// order always folds to the same root, regardless of how Push happens to
+// batch collapses internally.
+func TestPushRootDeterministic(t *testing.T) {
+	for _, n := range testLeafCounts {
+		for _, domainSep := range []bool{false, true} {
+			vals := leafVals(n)
+			roots := make([][]byte, 2)
+			for i := range roots {
+				tree := newTestTree(domainSep)
+				for _, val := range vals {
+					tree.Push(val)
+				}
+				root, err := tree.Root()
+				if err != nil {
+					t.Fatalf("n=%d domainSep=%v: Root: %v", n, domainSep, err)
+				}
+				roots[i] = root
+			}
+			if string(roots[0]) != string(roots[1]) {
+				t.Fatalf("n=%d domainSep=%v: Push/Root is not deterministic: %x != %x", n, domainSep, roots[0], roots[1])
+			}
+		}
+	}
+}
+
+func TestTrackProofProofVerifyProofRootRoundTrip(t *testing.T) {
+	for _, n := range testLeafCounts {
+		for _, domainSep := range []bool{false, true} {
+			vals := leafVals(n)
+			for tracked := 0; tracked < n; tracked++ {
+				tree := newTestTree(domainSep)
+				tree.TrackProof(tracked)
+				for _, val := range vals {
+					tree.Push(val)
+				}
+				root, err := tree.Root()
+				if err != nil {
+					t.Fatalf("n=%d domainSep=%v tracked=%d: Root: %v", n, domainSep, tracked, err)
+				}
+				p, err := tree.Proof()
+				if err != nil {
+					t.Fatalf("n=%d domainSep=%v tracked=%d: Proof: %v", n, domainSep, tracked, err)
+				}
+				if !tree.VerifyProofRoot(p, root) {
+					t.Fatalf("n=%d domainSep=%v tracked=%d: VerifyProofRoot returned false", n, domainSep, tracked)
+				}
+			}
+		}
+	}
+}
+
+func TestRootNoVals(t *testing.T) {
+	tree := newTestTree(false)
+	if _, err := tree.Root(); err == nil {
+		t.Fatal("expected error calling Root before any Push")
+	}
+}
+
+func TestProofNoTrackedLeaf(t *testing.T) {
+	tree := newTestTree(false)
+	tree.Push([]byte("leaf-0"))
+	if _, err := tree.Proof(); err == nil {
+		t.Fatal("expected error calling Proof without TrackProof")
+	}
+}
+
+func TestVerifyProofRootRejectsWrongRoot(t *testing.T) {
+	tree := newTestTree(false)
+	tree.TrackProof(1)
+	for _, val := range leafVals(5) {
+		tree.Push(val)
+	}
+	root, err := tree.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	p, err := tree.Proof()
+	if err != nil {
+		t.Fatalf("Proof: %v", err)
+	}
+	root[0] ^= 0xFF
+	if tree.VerifyProofRoot(p, root) {
+		t.Fatal("VerifyProofRoot accepted a tampered root")
+	}
+}
+
+func TestVerifyProofRootRejectsNilBranchEntry(t *testing.T) {
+	tree := newTestTree(false)
+	tree.TrackProof(1)
+	for _, val := range leafVals(5) {
+		tree.Push(val)
+	}
+	root, err := tree.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	p, err := tree.Proof()
+	if err != nil {
+		t.Fatalf("Proof: %v", err)
+	}
+	p.br = append(p.br, nil)
+	if tree.VerifyProofRoot(p, root) {
+		t.Fatal("VerifyProofRoot accepted a proof with a nil branch entry")
+	}
+}
+
+func TestPushSubtreeRootGrafting(t *testing.T) {
+	vals := leafVals(4)
+	direct := newTestTree(false)
+	for _, val := range vals {
+		direct.Push(val)
+	}
+	directRoot, err := direct.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+
+	// Pre-hash the first pair into a height-1 subtree root the same way
+	// collapseStack would, then graft it and push the remaining leaves.
+	pre := newTestTree(false)
+	pre.Push(vals[0])
+	pre.Push(vals[1])
+	subRoot, err := pre.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+
+	grafted := newTestTree(false)
+	grafted.PushSubtreeRoot(subRoot, 1)
+	grafted.Push(vals[2])
+	grafted.Push(vals[3])
+	graftedRoot, err := grafted.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	if string(graftedRoot) != string(directRoot) {
+		t.Fatalf("grafted root %x != direct root %x", graftedRoot, directRoot)
+	}
+}
@@ -0,0 +1,87 @@
+package merkle
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// MaxBlockSize bounds the block size chosen by DetermineBlockSize.
+const MaxBlockSize = 16 * 1024
+
+// DetermineBlockSize picks the largest power-of-two block size, no bigger
+// than MaxBlockSize, that evenly divides totalSize. It returns 0 if no
+// such size exists.
+func DetermineBlockSize(totalSize int) int {
+	if totalSize <= 0 {
+		return 0
+	}
+	for size := MaxBlockSize; size >= 1; size >>= 1 {
+		if totalSize%size == 0 {
+			return size
+		}
+	}
+	return 0
+}
+
+// streamRoot reads blockSize-sized values from r, hashing each into a leaf
+// and combining finished sibling pairs as soon as they appear on a local
+// stack, with the same collapseStack/foldStack logic Push uses, so memory
+// stays O(height) rather than O(leaves).
+func (t *Tree) streamRoot(r io.Reader, blockSize int) ([]byte, error) {
+	if blockSize <= 0 {
+		return nil, errors.New("Invalid block size")
+	}
+	var stack []subtree
+	buf := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Input size is not a multiple of block size")
+		} else if err != nil {
+			return nil, err
+		}
+		h := t.HashMaker()
+		h.Reset()
+		if t.domainSep {
+			h.Write([]byte{leafPrefix})
+		}
+		h.Write(buf[:n])
+		stack = append(stack, subtree{h: h.Sum(nil), height: 0})
+		stack = t.collapseStack(stack)
+	}
+	if len(stack) == 0 {
+		return nil, errors.New("No vals")
+	}
+	return t.foldStack(stack), nil
+}
+
+// ConstructFromReader builds a tree by streaming blockSize-sized values out
+// of r rather than requiring all values up front, so a large input can be
+// hashed without loading it fully into memory. It returns the root hash.
+// Unlike Construct, it doesn't populate levels, so it tracks streamBuilt
+// separately to guard against being called again on the same Tree.
+func (t *Tree) ConstructFromReader(r io.Reader, blockSize int) ([]byte, error) {
+	if !t.Empty() || t.streamBuilt {
+		return nil, errors.New("Tree is not empty")
+	}
+	root, err := t.streamRoot(r, blockSize)
+	if err != nil {
+		return nil, err
+	}
+	t.streamBuilt = true
+	return root, nil
+}
+
+// VerifyReader recomputes the root by streaming over r with the same block
+// size used to construct it, and reports whether it matches root.
+func (t *Tree) VerifyReader(r io.Reader, blockSize int, root []byte) (bool, error) {
+	h, err := t.streamRoot(r, blockSize)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(h, root), nil
+}
@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestDetermineBlockSize(t *testing.T) {
+	cases := []struct {
+		totalSize int
+		want      int
+	}{
+		{0, 0},
+		{-1, 0},
+		{1, 1},
+		{3, 1},
+		{16, 16},
+		{MaxBlockSize, MaxBlockSize},
+		{MaxBlockSize * 3, MaxBlockSize},
+		{MaxBlockSize + 1, 1},
+	}
+	for _, c := range cases {
+		if got := DetermineBlockSize(c.totalSize); got != c.want {
+			t.Errorf("DetermineBlockSize(%d) = %d, want %d", c.totalSize, got, c.want)
+		}
+	}
+}
+
+func TestConstructFromReaderVerifyReaderRoundTrip(t *testing.T) {
+	blockSize := 8
+	counts := []int{1, 2, 3, 7, 8, 9, 16}
+	for _, n := range counts {
+		for _, domainSep := range []bool{false, true} {
+			data := make([]byte, n*blockSize)
+			for i := range data {
+				data[i] = byte(i)
+			}
+			tree := newTestTree(domainSep)
+			root, err := tree.ConstructFromReader(bytes.NewReader(data), blockSize)
+			if err != nil {
+				t.Fatalf("n=%d domainSep=%v: ConstructFromReader: %v", n, domainSep, err)
+			}
+			verifyTree := newTestTree(domainSep)
+			ok, err := verifyTree.VerifyReader(bytes.NewReader(data), blockSize, root)
+			if err != nil {
+				t.Fatalf("n=%d domainSep=%v: VerifyReader: %v", n, domainSep, err)
+			}
+			if !ok {
+				t.Fatalf("n=%d domainSep=%v: VerifyReader returned false for matching data", n, domainSep)
+			}
+		}
+	}
+}
+
+func TestVerifyReaderRejectsTamperedData(t *testing.T) {
+	blockSize := 8
+	data := make([]byte, 5*blockSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	tree := NewTree(sha256.New())
+	root, err := tree.ConstructFromReader(bytes.NewReader(data), blockSize)
+	if err != nil {
+		t.Fatalf("ConstructFromReader: %v", err)
+	}
+	tampered := append([]byte(nil), data...)
+	tampered[0] ^= 0xFF
+	verifyTree := NewTree(sha256.New())
+	ok, err := verifyTree.VerifyReader(bytes.NewReader(tampered), blockSize, root)
+	if err != nil {
+		t.Fatalf("VerifyReader: %v", err)
+	}
+	if ok {
+		t.Fatal("VerifyReader accepted tampered data")
+	}
+}
+
+func TestConstructFromReaderRejectsPartialBlock(t *testing.T) {
+	blockSize := 8
+	data := make([]byte, blockSize+3)
+	tree := NewTree(sha256.New())
+	if _, err := tree.ConstructFromReader(bytes.NewReader(data), blockSize); err == nil {
+		t.Fatal("expected error for input size that isn't a multiple of block size")
+	}
+}
+
+func TestConstructFromReaderRejectsReentry(t *testing.T) {
+	blockSize := 8
+	data := make([]byte, 5*blockSize)
+	tree := NewTree(sha256.New())
+	if _, err := tree.ConstructFromReader(bytes.NewReader(data), blockSize); err != nil {
+		t.Fatalf("ConstructFromReader: %v", err)
+	}
+	if _, err := tree.ConstructFromReader(bytes.NewReader(data), blockSize); err == nil {
+		t.Fatal("expected error calling ConstructFromReader a second time on the same Tree")
+	}
+}
@@ -0,0 +1,178 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// splitPoint returns the largest power of two strictly less than n, which
+// is the size of the left subtree in the "simple tree" layout (the same
+// deterministic split Tendermint's SimpleTree uses). n must be >= 2.
+func splitPoint(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+func (t *Tree) hashPair(left, right []byte) []byte {
+	h := t.HashMaker()
+	h.Reset()
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+func (t *Tree) hashSimple(vals [][]byte) []byte {
+	if len(vals) == 1 {
+		h := t.HashMaker()
+		h.Reset()
+		h.Write(vals[0])
+		return h.Sum(nil)
+	}
+	k := splitPoint(len(vals))
+	left := t.hashSimple(vals[:k])
+	right := t.hashSimple(vals[k:])
+	return t.hashPair(left, right)
+}
+
+// ConstructSimple builds the root hash of vals using the deterministic,
+// unbalanced tree layout used by Tendermint's SimpleTree: the left
+// subtree always holds the largest power-of-two number of leaves smaller
+// than the total, the right subtree holds the rest, and internal nodes
+// are H(left || right) with no duplication of odd leaves. Unlike
+// Construct, the resulting shape depends only on the number of leaves,
+// not on how padding is applied.
+func (t *Tree) ConstructSimple(vals [][]byte) ([]byte, error) {
+	if len(vals) == 0 {
+		return nil, errors.New("No vals")
+	}
+	t.simpleVals = vals
+	t.simpleRoot = t.hashSimple(vals)
+	return t.simpleRoot, nil
+}
+
+// BranchSimple carries the sibling subtree hashes for a ProofSimple, in
+// order from the leaf up to the root.
+type BranchSimple [][]byte
+
+func (b BranchSimple) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("[BRANCH]\n")
+	for _, h := range b {
+		buf.WriteString(fmt.Sprintf("HASH(%x..)\n", h[:3]))
+	}
+	return buf.String()
+}
+
+// ProofSimple is a membership proof for Tree.ConstructSimple. Because the
+// simple tree's shape depends on the total leaf count, the proof carries
+// (Index, Total) so the verifier can re-derive the same split recursion
+// when walking Branch, rather than relying on a left/right bit per level.
+type ProofSimple struct {
+	h      []byte
+	Index  int
+	Total  int
+	Branch BranchSimple
+}
+
+func NewProofSimple(h []byte, index, total int, br BranchSimple) *ProofSimple {
+	return &ProofSimple{h, index, total, br}
+}
+
+func (p *ProofSimple) String() string {
+	return fmt.Sprintf("---PROOF---\n[%x..]\nindex=%d total=%d\n\n%v", p.h[:3], p.Index, p.Total, p.Branch)
+}
+
+// collectSimple walks the same split recursion as hashSimple, returning
+// the hash of vals while appending the sibling subtree's hash to br at
+// every level the leaf at index passes through.
+func (t *Tree) collectSimple(vals [][]byte, index int, br *BranchSimple) []byte {
+	if len(vals) == 1 {
+		h := t.HashMaker()
+		h.Reset()
+		h.Write(vals[0])
+		return h.Sum(nil)
+	}
+	k := splitPoint(len(vals))
+	if index < k {
+		left := t.collectSimple(vals[:k], index, br)
+		right := t.hashSimple(vals[k:])
+		*br = append(*br, right)
+		return t.hashPair(left, right)
+	}
+	right := t.collectSimple(vals[k:], index-k, br)
+	left := t.hashSimple(vals[:k])
+	*br = append(*br, left)
+	return t.hashPair(left, right)
+}
+
+// ComputeProofSimple builds a membership proof for val against the tree
+// most recently built with ConstructSimple.
+func (t *Tree) ComputeProofSimple(val []byte) (*ProofSimple, error) {
+	if t.simpleVals == nil {
+		return nil, errors.New("Tree has no simple tree constructed")
+	}
+	index := -1
+	for i, v := range t.simpleVals {
+		if bytes.Equal(v, val) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, errors.New("Val not found")
+	}
+	hh := t.HashMaker()
+	hh.Reset()
+	hh.Write(val)
+	h := hh.Sum(nil)
+	var br BranchSimple
+	t.collectSimple(t.simpleVals, index, &br)
+	return NewProofSimple(h, index, len(t.simpleVals), br), nil
+}
+
+// combineSimple mirrors collectSimple's recursion: it recurses down to the
+// leaf first, then combines with the next sibling hash off br as it
+// unwinds, so the consumption order matches the order siblings were
+// appended in. It reports ok=false instead of indexing out of range if br
+// runs out of entries, which a truncated or malformed Branch can trigger.
+func combineSimple(t *Tree, h []byte, index, total int, br BranchSimple, pos *int) (_ []byte, ok bool) {
+	if total == 1 {
+		return h, true
+	}
+	k := splitPoint(total)
+	if index < k {
+		left, ok := combineSimple(t, h, index, k, br, pos)
+		if !ok || *pos >= len(br) {
+			return nil, false
+		}
+		right := br[*pos]
+		*pos++
+		return t.hashPair(left, right), true
+	}
+	right, ok := combineSimple(t, h, index-k, total-k, br, pos)
+	if !ok || *pos >= len(br) {
+		return nil, false
+	}
+	left := br[*pos]
+	*pos++
+	return t.hashPair(left, right), true
+}
+
+// VerifyProofSimple reports whether p proves membership against the tree
+// most recently built with ConstructSimple.
+func (t *Tree) VerifyProofSimple(p *ProofSimple) bool {
+	if t.simpleRoot == nil || p.Total != len(t.simpleVals) || p.Index < 0 || p.Index >= p.Total {
+		return false
+	}
+	pos := 0
+	root, ok := combineSimple(t, p.h, p.Index, p.Total, p.Branch, &pos)
+	if !ok || pos != len(p.Branch) {
+		return false
+	}
+	return bytes.Equal(root, t.simpleRoot)
+}
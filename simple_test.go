@@ -0,0 +1,79 @@
+package merkle
+
+import (
+	"testing"
+)
+
+func TestConstructSimpleVerifyProofSimpleRoundTrip(t *testing.T) {
+	for _, n := range testLeafCounts {
+		vals := leafVals(n)
+		tree := newTestTree(false)
+		if _, err := tree.ConstructSimple(vals); err != nil {
+			t.Fatalf("n=%d: ConstructSimple: %v", n, err)
+		}
+		for _, val := range vals {
+			p, err := tree.ComputeProofSimple(val)
+			if err != nil {
+				t.Fatalf("n=%d val=%s: ComputeProofSimple: %v", n, val, err)
+			}
+			if !tree.VerifyProofSimple(p) {
+				t.Fatalf("n=%d val=%s: VerifyProofSimple returned false", n, val)
+			}
+		}
+	}
+}
+
+func TestComputeProofSimpleValNotFound(t *testing.T) {
+	tree := newTestTree(false)
+	if _, err := tree.ConstructSimple(leafVals(5)); err != nil {
+		t.Fatalf("ConstructSimple: %v", err)
+	}
+	if _, err := tree.ComputeProofSimple([]byte("not-a-leaf")); err == nil {
+		t.Fatal("expected error for a val that isn't a leaf")
+	}
+}
+
+func TestVerifyProofSimpleRejectsTamperedHash(t *testing.T) {
+	tree := newTestTree(false)
+	if _, err := tree.ConstructSimple(leafVals(7)); err != nil {
+		t.Fatalf("ConstructSimple: %v", err)
+	}
+	p, err := tree.ComputeProofSimple([]byte("leaf-3"))
+	if err != nil {
+		t.Fatalf("ComputeProofSimple: %v", err)
+	}
+	p.h[0] ^= 0xFF
+	if tree.VerifyProofSimple(p) {
+		t.Fatal("VerifyProofSimple accepted a tampered leaf hash")
+	}
+}
+
+func TestVerifyProofSimpleRejectsWrongTotal(t *testing.T) {
+	tree := newTestTree(false)
+	if _, err := tree.ConstructSimple(leafVals(7)); err != nil {
+		t.Fatalf("ConstructSimple: %v", err)
+	}
+	p, err := tree.ComputeProofSimple([]byte("leaf-3"))
+	if err != nil {
+		t.Fatalf("ComputeProofSimple: %v", err)
+	}
+	p.Total += 100
+	if tree.VerifyProofSimple(p) {
+		t.Fatal("VerifyProofSimple accepted a proof with a mismatched total")
+	}
+}
+
+func TestVerifyProofSimpleRejectsTruncatedBranch(t *testing.T) {
+	tree := newTestTree(false)
+	if _, err := tree.ConstructSimple(leafVals(7)); err != nil {
+		t.Fatalf("ConstructSimple: %v", err)
+	}
+	p, err := tree.ComputeProofSimple([]byte("leaf-3"))
+	if err != nil {
+		t.Fatalf("ComputeProofSimple: %v", err)
+	}
+	p.Branch = p.Branch[:0]
+	if tree.VerifyProofSimple(p) {
+		t.Fatal("VerifyProofSimple accepted a proof with a truncated branch")
+	}
+}